@@ -1,18 +1,84 @@
 package main
 
 import (
+	"autojobsearch-backend/internal/httpclient"
+	"autojobsearch-backend/internal/middleware"
 	"autojobsearch-backend/internal/proxy"
+	"autojobsearch-backend/internal/storage"
 	"log"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// Запросы к HH.ru API — это небольшие JSON/form тела (отклики, резюме)
+const defaultMaxBodyBytes = 64 << 10
+
+// Общий лимит на запрос к HH.ru вместе со всеми повторами
+const hhClientTimeout = 30 * time.Second
+
 func main() {
-	proxyHandler := proxy.NewHandler()
+	redisClient, err := storage.NewRedisClient(
+		getEnv("REDIS_URL", "redis://localhost:6379/0"),
+		getEnv("REDIS_NAMESPACE", "ajs"),
+	)
+	if err != nil {
+		log.Fatal("Redis connection failed:", err)
+	}
+	defer redisClient.Close()
+
+	hhClient := &http.Client{
+		Timeout:   hhClientTimeout,
+		Transport: httpclient.NewRetryTransport(http.DefaultTransport, getEnvInt("HH_MAX_RETRIES", 3)),
+	}
 
+	hhAPIURL := getEnv("HH_API_URL", "https://api.hh.ru")
+	proxyHandler := proxy.NewHandler(redisClient, hhClient, hhAPIURL, proxy.CacheConfig{
+		VacancyTTL: time.Duration(getEnvInt("PROXY_CACHE_TTL_SECONDS", 300)) * time.Second,
+		SearchTTL:  time.Duration(getEnvInt("PROXY_SEARCH_CACHE_TTL_SECONDS", 60)) * time.Second,
+	})
+
+	http.HandleFunc("/api/proxy/session", proxyHandler.CreateSession)
 	http.HandleFunc("/proxy/hh/", proxyHandler.HandleRequest)
 
+	maxBodyBytes := int64(getEnvInt("REQUEST_MAX_BODY_BYTES", defaultMaxBodyBytes))
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = defaultMaxBodyBytes
+	}
+	handler := middleware.MaxBodySizeMiddleware(maxBodyBytes)(http.DefaultServeMux)
+	handler = middleware.CORSMiddleware(middleware.CORSConfig{
+		AllowedOrigins:   getEnvList("CORS_ALLOWED_ORIGINS"),
+		AllowCredentials: os.Getenv("CORS_ALLOW_CREDENTIALS") == "true",
+	})(handler)
+
 	log.Println("Starting secure proxy server on :8080")
-	if err := http.ListenAndServe(":8080", nil); err != nil {
+	if err := http.ListenAndServe(":8080", handler); err != nil {
 		log.Fatal("Server failed:", err)
 	}
 }
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return fallback
+}
+
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,128 @@
+package httpclient
+
+import (
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultBaseDelay = 200 * time.Millisecond
+	jitterFraction   = 0.25
+
+	// Дольше этого Retry-After не ждем, а отдаем 429 вызывающему
+	maxRetryAfterWait = 30 * time.Second
+)
+
+// RetryTransport повторяет запросы при сетевых ошибках, ответах 5xx и 429
+// с экспоненциальной задержкой и случайным разбросом ±25 %. Повторяются только
+// идемпотентные методы: POST (например, отклик на вакансию) мог быть уже
+// обработан HH.ru до ошибки. Запросы с телом, которое нельзя прочитать
+// повторно (без GetBody), тоже не повторяются.
+type RetryTransport struct {
+	Base       http.RoundTripper
+	MaxRetries int
+	BaseDelay  time.Duration
+}
+
+func NewRetryTransport(base http.RoundTripper, maxRetries int) *RetryTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &RetryTransport{
+		Base:       base,
+		MaxRetries: maxRetries,
+		BaseDelay:  defaultBaseDelay,
+	}
+}
+
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	replayable := isIdempotent(req.Method) &&
+		(req.Body == nil || req.Body == http.NoBody || req.GetBody != nil)
+
+	for attempt := 0; ; attempt++ {
+		// RoundTripper не должен изменять исходный запрос, поэтому повторы
+		// отправляются копией со свежим телом из GetBody
+		attemptReq := req
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := t.Base.RoundTrip(attemptReq)
+		if attempt >= t.MaxRetries || !replayable || !shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt)
+		if resp != nil {
+			if resp.StatusCode == http.StatusTooManyRequests {
+				if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+					if retryAfter > maxRetryAfterWait {
+						return resp, nil
+					}
+					delay = retryAfter
+				}
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (t *RetryTransport) backoff(attempt int) time.Duration {
+	delay := float64(t.BaseDelay) * float64(uint(1)<<attempt)
+	jitter := 1 - jitterFraction + rand.Float64()*2*jitterFraction
+	return time.Duration(delay * jitter)
+}
+
+// isIdempotent повторяет правило net/http: пустой метод означает GET
+func isIdempotent(method string) bool {
+	switch method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace,
+		http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return req.Context().Err() == nil
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(at); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
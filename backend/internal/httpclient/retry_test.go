@@ -0,0 +1,199 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newSequenceServer отвечает статусами из statuses по очереди, последний
+// статус повторяется для всех следующих запросов
+func newSequenceServer(t *testing.T, statuses []int, header http.Header) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&calls, 1))
+		io.Copy(io.Discard, r.Body)
+
+		status := statuses[len(statuses)-1]
+		if n <= len(statuses) {
+			status = statuses[n-1]
+		}
+		for name, values := range header {
+			w.Header()[name] = values
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &calls
+}
+
+func newTestClient(maxRetries int) *http.Client {
+	transport := NewRetryTransport(nil, maxRetries)
+	transport.BaseDelay = time.Millisecond
+	return &http.Client{Transport: transport}
+}
+
+func TestRetryTransport_RetriesServerErrors(t *testing.T) {
+	srv, calls := newSequenceServer(t, []int{503, 503, 200}, nil)
+
+	resp, err := newTestClient(3).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if *calls != 3 {
+		t.Fatalf("calls = %d, want 3", *calls)
+	}
+}
+
+func TestRetryTransport_StopsAfterMaxRetries(t *testing.T) {
+	srv, calls := newSequenceServer(t, []int{503}, nil)
+
+	resp, err := newTestClient(2).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", resp.StatusCode)
+	}
+	if *calls != 3 {
+		t.Fatalf("calls = %d, want 3 (1 + 2 retries)", *calls)
+	}
+}
+
+func TestRetryTransport_DoesNotRetryClientErrors(t *testing.T) {
+	for _, status := range []int{400, 401, 403, 404} {
+		srv, calls := newSequenceServer(t, []int{status, 200}, nil)
+
+		resp, err := newTestClient(3).Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != status {
+			t.Fatalf("status = %d, want %d", resp.StatusCode, status)
+		}
+		if *calls != 1 {
+			t.Fatalf("%d: calls = %d, want 1", status, *calls)
+		}
+	}
+}
+
+func TestRetryTransport_TooManyRequestsRetryAfter(t *testing.T) {
+	srv, calls := newSequenceServer(t, []int{429, 200}, http.Header{"Retry-After": {"1"}})
+
+	start := time.Now()
+	resp, err := newTestClient(3).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if *calls != 2 {
+		t.Fatalf("calls = %d, want 2", *calls)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Fatalf("waited %v, want at least Retry-After of 1s", elapsed)
+	}
+}
+
+func TestRetryTransport_LongRetryAfterIsReturned(t *testing.T) {
+	srv, calls := newSequenceServer(t, []int{429, 200}, http.Header{"Retry-After": {"120"}})
+
+	resp, err := newTestClient(3).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", resp.StatusCode)
+	}
+	if *calls != 1 {
+		t.Fatalf("calls = %d, want 1", *calls)
+	}
+}
+
+func TestRetryTransport_StreamedBodyIsNotReplayed(t *testing.T) {
+	srv, calls := newSequenceServer(t, []int{503, 200}, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL, io.NopCloser(strings.NewReader("message")))
+	resp, err := newTestClient(3).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", resp.StatusCode)
+	}
+	if *calls != 1 {
+		t.Fatalf("calls = %d, want 1", *calls)
+	}
+}
+
+func TestRetryTransport_NonIdempotentMethodIsNotRetried(t *testing.T) {
+	srv, calls := newSequenceServer(t, []int{503, 200}, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL, nil)
+	resp, err := newTestClient(3).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", resp.StatusCode)
+	}
+	if *calls != 1 {
+		t.Fatalf("calls = %d, want 1", *calls)
+	}
+}
+
+func TestRetryTransport_ReplayableBodyDoesNotMutateRequest(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if len(bodies) < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodPut, srv.URL, strings.NewReader("message"))
+	originalBody := req.Body
+
+	resp, err := newTestClient(3).Transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if len(bodies) != 2 || bodies[0] != "message" || bodies[1] != "message" {
+		t.Fatalf("bodies = %q, want the body sent twice", bodies)
+	}
+	if req.Body != originalBody {
+		t.Fatal("RoundTrip replaced the caller's request body")
+	}
+}
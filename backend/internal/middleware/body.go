@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+)
+
+// MaxBodySizeMiddleware ограничивает размер тела запроса maxBytes байтами.
+// Запросы с заведомо большим Content-Length отклоняются сразу, остальные
+// получают http.MaxBytesReader, и обработчик сам возвращает 413 при
+// ошибке *http.MaxBytesError.
+func MaxBodySizeMiddleware(maxBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > maxBytes {
+				http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
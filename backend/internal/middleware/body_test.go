@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testMaxBodyBytes = 16
+
+// readBodyHandler ведет себя как прокси: читает тело и отвечает 413 на
+// *http.MaxBytesError
+func readBodyHandler(w http.ResponseWriter, r *http.Request) {
+	if _, err := io.ReadAll(r.Body); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Failed to read body", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestMaxBodySizeMiddleware_RejectsLargeContentLength(t *testing.T) {
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("x", 100)))
+	rec := httptest.NewRecorder()
+	MaxBodySizeMiddleware(testMaxBodyBytes)(next).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusRequestEntityTooLarge)
+	}
+	if called {
+		t.Fatal("next handler must not be called for an oversized Content-Length")
+	}
+}
+
+func TestMaxBodySizeMiddleware_ChunkedBodyOverLimit(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", io.NopCloser(strings.NewReader(strings.Repeat("x", 100))))
+	req.ContentLength = -1
+	rec := httptest.NewRecorder()
+	MaxBodySizeMiddleware(testMaxBodyBytes)(http.HandlerFunc(readBodyHandler)).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestMaxBodySizeMiddleware_Limit(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		chunked bool
+		want    int
+	}{
+		{"exactly at limit", testMaxBodyBytes, false, http.StatusOK},
+		{"one byte over", testMaxBodyBytes + 1, false, http.StatusRequestEntityTooLarge},
+		{"chunked exactly at limit", testMaxBodyBytes, true, http.StatusOK},
+		{"chunked one byte over", testMaxBodyBytes + 1, true, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader = strings.NewReader(strings.Repeat("x", tt.size))
+			if tt.chunked {
+				body = io.NopCloser(body)
+			}
+			req := httptest.NewRequest(http.MethodPost, "/", body)
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+
+			rec := httptest.NewRecorder()
+			MaxBodySizeMiddleware(testMaxBodyBytes)(http.HandlerFunc(readBodyHandler)).ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	corsAllowedMethods = "GET, POST, PUT, DELETE, OPTIONS"
+	corsAllowedHeaders = "Content-Type, Authorization, X-HH-Access-Token, X-Proxy-Session-Token"
+	corsMaxAge         = "600"
+)
+
+// CORSConfig описывает, каким источникам разрешены кросс-доменные запросы.
+// Элемент AllowedOrigins — точный origin ("https://app.example.com"),
+// шаблон поддоменов ("https://*.example.com") или "*" для любого origin
+// (только без AllowCredentials).
+type CORSConfig struct {
+	AllowedOrigins   []string
+	AllowCredentials bool
+}
+
+// CORSMiddleware возвращает разрешенный origin в Access-Control-Allow-Origin
+// вместо "*", поэтому его можно использовать вместе с credentials.
+// Preflight-запросы от неразрешенных источников получают 403.
+func CORSMiddleware(cfg CORSConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Origin")
+			allowed := cfg.allows(origin)
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+			if !allowed {
+				if preflight {
+					http.Error(w, "Origin not allowed", http.StatusForbidden)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			if cfg.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if preflight {
+				w.Header().Set("Access-Control-Allow-Methods", corsAllowedMethods)
+				w.Header().Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+				w.Header().Set("Access-Control-Max-Age", corsMaxAge)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func (c CORSConfig) allows(origin string) bool {
+	origin = strings.ToLower(origin)
+
+	for _, allowed := range c.AllowedOrigins {
+		allowed = strings.ToLower(allowed)
+
+		// С credentials "*" игнорируется: иначе любой сайт получил бы
+		// доступ к ответам с cookies пользователя
+		if (allowed == "*" && !c.AllowCredentials) || allowed == origin {
+			return true
+		}
+
+		// "https://*.example.com" разрешает любой поддомен example.com,
+		// но не сам example.com
+		prefix, suffix, ok := strings.Cut(allowed, "*")
+		if !ok || !strings.HasPrefix(suffix, ".") {
+			continue
+		}
+		// Длину проверяем до среза: в "https://api.*.example.com" префикс и
+		// суффикс могут перекрываться в origin "https://api.example.com"
+		if len(origin) <= len(prefix)+len(suffix) {
+			continue
+		}
+		if !strings.HasPrefix(origin, prefix) || !strings.HasSuffix(origin, suffix) {
+			continue
+		}
+		subdomain := origin[len(prefix) : len(origin)-len(suffix)]
+		if !strings.ContainsAny(subdomain, "/:") {
+			return true
+		}
+	}
+	return false
+}
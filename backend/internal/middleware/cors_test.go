@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func serveCORS(cfg CORSConfig, method, origin string) *httptest.ResponseRecorder {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(method, "/proxy/hh/vacancies", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	if method == http.MethodOptions {
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	}
+
+	rec := httptest.NewRecorder()
+	CORSMiddleware(cfg)(next).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	exact := CORSConfig{AllowedOrigins: []string{"https://app.example.com"}}
+	wildcard := CORSConfig{AllowedOrigins: []string{"https://*.example.com"}}
+	overlapping := CORSConfig{AllowedOrigins: []string{"https://api.*.example.com"}}
+
+	tests := []struct {
+		name       string
+		cfg        CORSConfig
+		method     string
+		origin     string
+		wantStatus int
+		wantOrigin string
+	}{
+		{"exact match", exact, http.MethodGet, "https://app.example.com", http.StatusOK, "https://app.example.com"},
+		{"exact match is case-insensitive", exact, http.MethodGet, "https://APP.example.com", http.StatusOK, "https://APP.example.com"},
+		{"exact match preflight", exact, http.MethodOptions, "https://app.example.com", http.StatusNoContent, "https://app.example.com"},
+		{"no match", exact, http.MethodGet, "https://evil.com", http.StatusOK, ""},
+		{"no match preflight", exact, http.MethodOptions, "https://evil.com", http.StatusForbidden, ""},
+		{"no origin", exact, http.MethodGet, "", http.StatusOK, ""},
+		{"subdomain", wildcard, http.MethodGet, "https://app.example.com", http.StatusOK, "https://app.example.com"},
+		{"nested subdomain", wildcard, http.MethodGet, "https://a.b.example.com", http.StatusOK, "https://a.b.example.com"},
+		{"wildcard excludes apex", wildcard, http.MethodOptions, "https://example.com", http.StatusForbidden, ""},
+		{"wildcard checks suffix boundary", wildcard, http.MethodOptions, "https://evilexample.com", http.StatusForbidden, ""},
+		{"wildcard checks scheme", wildcard, http.MethodOptions, "http://app.example.com", http.StatusForbidden, ""},
+		{"wildcard rejects port", wildcard, http.MethodOptions, "https://app.example.com:8443", http.StatusForbidden, ""},
+		{"overlapping prefix and suffix", overlapping, http.MethodOptions, "https://api.example.com", http.StatusForbidden, ""},
+		{"overlapping pattern match", overlapping, http.MethodGet, "https://api.v1.example.com", http.StatusOK, "https://api.v1.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveCORS(tt.cfg, tt.method, tt.origin)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Fatalf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+			if rec.Header().Get("Access-Control-Allow-Credentials") != "" {
+				t.Fatal("credentials header must not be set when AllowCredentials is false")
+			}
+		})
+	}
+}
+
+func TestCORSMiddleware_Credentials(t *testing.T) {
+	tests := []struct {
+		name       string
+		origins    []string
+		origin     string
+		wantStatus int
+		wantOrigin string
+		wantCreds  string
+	}{
+		{"listed origin", []string{"https://app.example.com"}, "https://app.example.com", http.StatusNoContent, "https://app.example.com", "true"},
+		{"subdomain pattern", []string{"https://*.example.com"}, "https://app.example.com", http.StatusNoContent, "https://app.example.com", "true"},
+		{"star is ignored", []string{"*"}, "https://evil.com", http.StatusForbidden, "", ""},
+		{"star next to listed origin", []string{"*", "https://app.example.com"}, "https://app.example.com", http.StatusNoContent, "https://app.example.com", "true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := CORSConfig{AllowedOrigins: tt.origins, AllowCredentials: true}
+			rec := serveCORS(cfg, http.MethodOptions, tt.origin)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Fatalf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != tt.wantCreds {
+				t.Fatalf("Access-Control-Allow-Credentials = %q, want %q", got, tt.wantCreds)
+			}
+		})
+	}
+}
+
+func TestCORSMiddleware_StarWithoutCredentials(t *testing.T) {
+	rec := serveCORS(CORSConfig{AllowedOrigins: []string{"*"}}, http.MethodGet, "https://any.site")
+
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://any.site" {
+		t.Fatalf("Access-Control-Allow-Origin = %q, want the request origin, never \"*\"", got)
+	}
+	if got := rec.Header().Get("Vary"); got != "Origin" {
+		t.Fatalf("Vary = %q, want Origin", got)
+	}
+}
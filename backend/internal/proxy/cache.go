@@ -0,0 +1,115 @@
+package proxy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	cacheKeyPrefix     = "proxy_cache:"
+	cacheKeysSetPrefix = "proxy_cache_keys:"
+)
+
+// CacheConfig задает время жизни закэшированных ответов HH.ru.
+// Нулевое значение отключает кэширование соответствующих запросов.
+type CacheConfig struct {
+	VacancyTTL time.Duration // GET /vacancies/{id}
+	SearchTTL  time.Duration // GET /vacancies
+}
+
+// maxTTL — время жизни множества ключей соискателя: оно не должно истечь
+// раньше любой из записей, которые в нем перечислены
+func (c CacheConfig) maxTTL() time.Duration {
+	if c.VacancyTTL > c.SearchTTL {
+		return c.VacancyTTL
+	}
+	return c.SearchTTL
+}
+
+type cachedResponse struct {
+	ContentType string    `json:"content_type"`
+	Body        []byte    `json:"body"`
+	CachedAt    time.Time `json:"cached_at"`
+}
+
+// Ответы HH.ru на авторизованные запросы содержат данные пользователя
+// (например, relations с откликами), поэтому кэш ведется отдельно для
+// каждого соискателя, которого определяем по хэшу его access token.
+func cacheOwner(userToken string) string {
+	sum := sha256.Sum256([]byte(userToken))
+	return hex.EncodeToString(sum[:])
+}
+
+func cacheKey(owner, path string, r *http.Request) string {
+	return cacheKeyPrefix + owner + ":" + path + "?" + r.URL.Query().Encode()
+}
+
+// cacheTTL возвращает время жизни ответа и false, если запрос не кэшируется
+func (h *Handler) cacheTTL(r *http.Request, path string) (time.Duration, bool) {
+	if r.Method != http.MethodGet {
+		return 0, false
+	}
+
+	var ttl time.Duration
+	switch path = strings.Trim(path, "/"); {
+	case path == "vacancies":
+		ttl = h.cache.SearchTTL
+	case strings.HasPrefix(path, "vacancies/"):
+		ttl = h.cache.VacancyTTL
+	}
+	return ttl, ttl > 0
+}
+
+func (h *Handler) readCache(ctx context.Context, key string) (*cachedResponse, bool) {
+	data, err := h.store.Get(ctx, key)
+	if err != nil {
+		return nil, false
+	}
+
+	var cached cachedResponse
+	if err := json.Unmarshal([]byte(data), &cached); err != nil {
+		return nil, false
+	}
+	return &cached, true
+}
+
+func (h *Handler) writeCache(ctx context.Context, owner, key string, resp *http.Response, body []byte, ttl time.Duration) {
+	data, err := json.Marshal(cachedResponse{
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        body,
+		CachedAt:    time.Now(),
+	})
+	if err != nil {
+		return
+	}
+
+	// Ошибка записи в кэш не должна ломать ответ клиенту. Ключ запоминаем
+	// в множестве соискателя, чтобы сбросить его кэш без SCAN по Redis.
+	if err := h.store.Set(ctx, key, string(data), ttl); err != nil {
+		return
+	}
+	h.store.AddToSet(ctx, cacheKeysSetPrefix+owner, key, h.cache.maxTTL())
+}
+
+// invalidateCache удаляет все закэшированные ответы соискателя, например
+// после отклика, когда relations в вакансиях меняются
+func (h *Handler) invalidateCache(ctx context.Context, owner string) {
+	h.store.DeleteSetMembers(ctx, cacheKeysSetPrefix+owner)
+}
+
+func writeCachedResponse(w http.ResponseWriter, cached *cachedResponse) {
+	age := int(time.Since(cached.CachedAt).Seconds())
+
+	if cached.ContentType != "" {
+		w.Header().Set("Content-Type", cached.ContentType)
+	}
+	w.Header().Set("X-Cache-Age", strconv.Itoa(age))
+	w.WriteHeader(http.StatusOK)
+	w.Write(cached.Body)
+}
@@ -0,0 +1,104 @@
+package proxy
+
+import (
+	"net/http"
+	"testing"
+)
+
+func vacanciesUpstream(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"items":[]}`))
+}
+
+func TestHandleRequest_CachesIdenticalSearches(t *testing.T) {
+	h, _, up := newTestHandler(t, vacanciesUpstream)
+
+	first := doProxyRequest(h, http.MethodGet, "/proxy/hh/vacancies?text=go&area=1")
+	second := doProxyRequest(h, http.MethodGet, "/proxy/hh/vacancies?area=1&text=go")
+
+	if up.Calls() != 1 {
+		t.Fatalf("upstream calls = %d, want 1", up.Calls())
+	}
+	if first.Header().Get("X-Cache-Age") != "" {
+		t.Fatal("first response must come from HH.ru")
+	}
+	if second.Header().Get("X-Cache-Age") == "" {
+		t.Fatal("second response must come from cache")
+	}
+	if second.Body.String() != `{"items":[]}` {
+		t.Fatalf("cached body = %q", second.Body.String())
+	}
+	if got := second.Header().Get("Content-Type"); got != "application/json" {
+		t.Fatalf("cached Content-Type = %q", got)
+	}
+}
+
+func TestHandleRequest_CachesVacancyDetails(t *testing.T) {
+	h, _, up := newTestHandler(t, vacanciesUpstream)
+
+	doProxyRequest(h, http.MethodGet, "/proxy/hh/vacancies/123")
+	doProxyRequest(h, http.MethodGet, "/proxy/hh/vacancies/123")
+	doProxyRequest(h, http.MethodGet, "/proxy/hh/vacancies/456")
+
+	if up.Calls() != 2 {
+		t.Fatalf("upstream calls = %d, want 2", up.Calls())
+	}
+}
+
+func TestHandleRequest_DoesNotCacheOtherRequests(t *testing.T) {
+	h, _, up := newTestHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/vacancies/404" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	doProxyRequest(h, http.MethodGet, "/proxy/hh/resumes/mine")
+	doProxyRequest(h, http.MethodGet, "/proxy/hh/resumes/mine")
+	doProxyRequest(h, http.MethodGet, "/proxy/hh/vacancies/404")
+	doProxyRequest(h, http.MethodGet, "/proxy/hh/vacancies/404")
+
+	if up.Calls() != 4 {
+		t.Fatalf("upstream calls = %d, want 4", up.Calls())
+	}
+}
+
+func TestHandleRequest_NegotiationInvalidatesCache(t *testing.T) {
+	h, store, up := newTestHandler(t, vacanciesUpstream)
+
+	doProxyRequest(h, http.MethodGet, "/proxy/hh/vacancies?text=go")
+	doProxyRequest(h, http.MethodGet, "/proxy/hh/vacancies/123")
+	doProxyRequest(h, http.MethodPost, "/proxy/hh/negotiations?vacancy_id=123")
+
+	if owner := cacheOwner(testHHToken); len(store.sets[cacheKeysSetPrefix+owner]) != 0 {
+		t.Fatal("applicant's cache key set must be cleared")
+	}
+
+	doProxyRequest(h, http.MethodGet, "/proxy/hh/vacancies?text=go")
+	doProxyRequest(h, http.MethodGet, "/proxy/hh/vacancies/123")
+
+	if up.Calls() != 5 {
+		t.Fatalf("upstream calls = %d, want 5", up.Calls())
+	}
+}
+
+func TestHandleRequest_FailedNegotiationKeepsCache(t *testing.T) {
+	h, _, up := newTestHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"items":[]}`))
+	})
+
+	doProxyRequest(h, http.MethodGet, "/proxy/hh/vacancies/123")
+	doProxyRequest(h, http.MethodPost, "/proxy/hh/negotiations?vacancy_id=123")
+	doProxyRequest(h, http.MethodGet, "/proxy/hh/vacancies/123")
+
+	if up.Calls() != 2 {
+		t.Fatalf("upstream calls = %d, want 2", up.Calls())
+	}
+}
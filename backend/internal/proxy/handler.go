@@ -1,17 +1,36 @@
 package proxy
 
 import (
+	"autojobsearch-backend/internal/storage"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"time"
 )
 
+// Store хранит сессии и кэш прокси. В продакшене это storage.RedisClient;
+// Get должен возвращать storage.ErrNotFound для отсутствующих ключей.
+type Store interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key, value string, ttl time.Duration) error
+	AddToSet(ctx context.Context, setKey, member string, ttl time.Duration) error
+	DeleteSetMembers(ctx context.Context, setKey string) error
+}
+
 type Handler struct {
 	allowedEndpoints map[string]bool
+	store            Store
+	httpClient       *http.Client
+	baseURL          string
+	cache            CacheConfig
 }
 
-func NewHandler() *Handler {
+// NewHandler создает прокси к HH.ru API по адресу baseURL
+// (например, "https://api.hh.ru")
+func NewHandler(store Store, httpClient *http.Client, baseURL string, cache CacheConfig) *Handler {
 	return &Handler{
 		allowedEndpoints: map[string]bool{
 			"vacancies":    true,
@@ -19,14 +38,28 @@ func NewHandler() *Handler {
 			"resumes":      true,
 			"employers":    true,
 		},
+		store:      store,
+		httpClient: httpClient,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		cache:      cache,
 	}
 }
 
 func (h *Handler) HandleRequest(w http.ResponseWriter, r *http.Request) {
-	// 1. Извлечь токен пользователя из заголовка
-	userToken := r.Header.Get("X-HH-Access-Token")
-	if userToken == "" {
-		http.Error(w, "Access token required", http.StatusBadRequest)
+	// 1. Получить токен пользователя по сессии прокси
+	sessionToken := r.Header.Get("X-Proxy-Session-Token")
+	if sessionToken == "" {
+		http.Error(w, "Proxy session token required", http.StatusBadRequest)
+		return
+	}
+
+	userToken, err := h.lookupSession(r.Context(), sessionToken)
+	if errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, "Invalid or expired proxy session", http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to check proxy session", http.StatusInternalServerError)
 		return
 	}
 
@@ -40,34 +73,81 @@ func (h *Handler) HandleRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 4. Создать запрос к HH.ru
-	hhURL := fmt.Sprintf("https://api.hh.ru/%s?%s", path, r.URL.RawQuery)
-	proxyReq, err := http.NewRequest(r.Method, hhURL, r.Body)
+	// 4. Вернуть ответ из кэша, если он есть
+	owner := cacheOwner(userToken)
+	ttl, cacheable := h.cacheTTL(r, path)
+	key := cacheKey(owner, path, r)
+	if cacheable {
+		if cached, ok := h.readCache(r.Context(), key); ok {
+			writeCachedResponse(w, cached)
+			return
+		}
+	}
+
+	// 5. Создать запрос к HH.ru
+	// Запрос без тела отправляем с http.NoBody: тело, обернутое
+	// MaxBytesReader, нельзя прочитать повторно, и ретраи бы не сработали
+	body := r.Body
+	if r.ContentLength == 0 && len(r.TransferEncoding) == 0 {
+		body = http.NoBody
+	}
+
+	hhURL := fmt.Sprintf("%s/%s?%s", h.baseURL, path, r.URL.RawQuery)
+	proxyReq, err := http.NewRequestWithContext(r.Context(), r.Method, hhURL, body)
 	if err != nil {
 		http.Error(w, "Failed to create proxy request", http.StatusInternalServerError)
 		return
 	}
 
-	// 5. Установить заголовки (только необходимые)
+	// 6. Установить заголовки (только необходимые)
 	proxyReq.Header.Set("Authorization", "Bearer "+userToken)
 	proxyReq.Header.Set("User-Agent", r.Header.Get("User-Agent"))
 	proxyReq.Header.Set("Content-Type", r.Header.Get("Content-Type"))
 
-	// 6. Выполнить запрос
-	client := &http.Client{}
-	resp, err := client.Do(proxyReq)
+	// 7. Выполнить запрос
+	resp, err := h.httpClient.Do(proxyReq)
 	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
 		http.Error(w, "Failed to reach HH.ru", http.StatusBadGateway)
 		return
 	}
 	defer resp.Body.Close()
 
-	// 7. Скопировать ответ клиенту
-	for key, values := range resp.Header {
+	// 8. Обновить кэш: успешный отклик меняет relations в вакансиях
+	if r.Method == http.MethodPost && endpoint == "negotiations" && resp.StatusCode < 300 {
+		h.invalidateCache(r.Context(), owner)
+	}
+
+	// 9. Сохранить успешный ответ в кэш
+	var respBody []byte
+	buffered := cacheable && resp.StatusCode == http.StatusOK
+	if buffered {
+		respBody, err = io.ReadAll(resp.Body)
+		if err != nil {
+			http.Error(w, "Failed to read HH.ru response", http.StatusBadGateway)
+			return
+		}
+		h.writeCache(r.Context(), owner, key, resp, respBody, ttl)
+	}
+
+	// 10. Скопировать ответ клиенту. CORS-заголовки HH.ru не передаем:
+	// политику CORS задает только CORSMiddleware
+	for name, values := range resp.Header {
+		if strings.HasPrefix(http.CanonicalHeaderKey(name), "Access-Control-") {
+			continue
+		}
 		for _, value := range values {
-			w.Header().Add(key, value)
+			w.Header().Add(name, value)
 		}
 	}
 	w.WriteHeader(resp.StatusCode)
+	if buffered {
+		w.Write(respBody)
+		return
+	}
 	io.Copy(w, resp.Body)
 }
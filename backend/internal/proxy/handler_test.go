@@ -0,0 +1,176 @@
+package proxy
+
+import (
+	"autojobsearch-backend/internal/storage"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testHHToken      = "hh-access-token"
+	testSessionToken = "test-session"
+)
+
+// memStore — Store в памяти для тестов, TTL не учитывается
+type memStore struct {
+	mu   sync.Mutex
+	data map[string]string
+	sets map[string]map[string]bool
+}
+
+func newMemStore() *memStore {
+	return &memStore{
+		data: make(map[string]string),
+		sets: make(map[string]map[string]bool),
+	}
+}
+
+func (s *memStore) Get(ctx context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, ok := s.data[key]
+	if !ok {
+		return "", storage.ErrNotFound
+	}
+	return value, nil
+}
+
+func (s *memStore) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data[key] = value
+	return nil
+}
+
+func (s *memStore) AddToSet(ctx context.Context, setKey, member string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sets[setKey] == nil {
+		s.sets[setKey] = make(map[string]bool)
+	}
+	s.sets[setKey][member] = true
+	return nil
+}
+
+func (s *memStore) DeleteSetMembers(ctx context.Context, setKey string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for member := range s.sets[setKey] {
+		delete(s.data, member)
+	}
+	delete(s.sets, setKey)
+	return nil
+}
+
+// upstream — заглушка HH.ru API, которая считает запросы
+type upstream struct {
+	mu      sync.Mutex
+	calls   int
+	handler http.HandlerFunc
+}
+
+func (u *upstream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	u.mu.Lock()
+	u.calls++
+	u.mu.Unlock()
+
+	u.handler(w, r)
+}
+
+func (u *upstream) Calls() int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.calls
+}
+
+func newTestHandler(t *testing.T, handler http.HandlerFunc) (*Handler, *memStore, *upstream) {
+	t.Helper()
+
+	up := &upstream{handler: handler}
+	srv := httptest.NewServer(up)
+	t.Cleanup(srv.Close)
+
+	store := newMemStore()
+	store.Set(context.Background(), sessionKeyPrefix+testSessionToken, testHHToken, sessionTTL)
+
+	h := NewHandler(store, srv.Client(), srv.URL, CacheConfig{
+		VacancyTTL: 5 * time.Minute,
+		SearchTTL:  time.Minute,
+	})
+	return h, store, up
+}
+
+func doProxyRequest(h *Handler, method, target string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	req.Header.Set("X-Proxy-Session-Token", testSessionToken)
+
+	rec := httptest.NewRecorder()
+	h.HandleRequest(rec, req)
+	return rec
+}
+
+func TestHandleRequest_SessionRequired(t *testing.T) {
+	h, _, up := newTestHandler(t, func(w http.ResponseWriter, r *http.Request) {})
+
+	req := httptest.NewRequest(http.MethodGet, "/proxy/hh/vacancies", nil)
+	rec := httptest.NewRecorder()
+	h.HandleRequest(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("without session: status = %d, want 400", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/proxy/hh/vacancies", nil)
+	req.Header.Set("X-Proxy-Session-Token", "unknown")
+	rec = httptest.NewRecorder()
+	h.HandleRequest(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("unknown session: status = %d, want 401", rec.Code)
+	}
+
+	if up.Calls() != 0 {
+		t.Fatalf("upstream calls = %d, want 0", up.Calls())
+	}
+}
+
+func TestHandleRequest_ForwardsWithUserToken(t *testing.T) {
+	h, _, _ := newTestHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer "+testHHToken {
+			t.Errorf("Authorization = %q", got)
+		}
+		if r.URL.Path != "/resumes/mine" {
+			t.Errorf("path = %q, want /resumes/mine", r.URL.Path)
+		}
+	})
+
+	if rec := doProxyRequest(h, http.MethodGet, "/proxy/hh/resumes/mine"); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+}
+
+func TestHandleRequest_DropsUpstreamCORSHeaders(t *testing.T) {
+	h, _, _ := newTestHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+		w.Header().Set("X-Request-Id", "42")
+	})
+
+	rec := doProxyRequest(h, http.MethodGet, "/proxy/hh/resumes/mine")
+
+	for name := range rec.Header() {
+		if strings.HasPrefix(name, "Access-Control-") {
+			t.Fatalf("upstream header %s must not be forwarded", name)
+		}
+	}
+	if rec.Header().Get("X-Request-Id") != "42" {
+		t.Fatal("other upstream headers must be forwarded")
+	}
+}
@@ -0,0 +1,68 @@
+package proxy
+
+import (
+	"autojobsearch-backend/internal/httpclient"
+	"autojobsearch-backend/internal/middleware"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Прокси работает за MaxBodySizeMiddleware, который оборачивает r.Body.
+// Запросы без тела все равно должны повторяться RetryTransport.
+func TestHandleRequest_RetriesBehindBodyLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		target    string
+		body      string
+		wantCode  int
+		wantCalls int
+	}{
+		{"GET without body is retried", http.MethodGet, "/proxy/hh/negotiations", "", http.StatusOK, 3},
+		{"POST with body is sent once", http.MethodPost, "/proxy/hh/negotiations", `{"message":"hi"}`, http.StatusServiceUnavailable, 1},
+		{"POST without body is sent once", http.MethodPost, "/proxy/hh/negotiations?vacancy_id=1", "", http.StatusServiceUnavailable, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			up := &upstream{}
+			up.handler = func(w http.ResponseWriter, r *http.Request) {
+				io.Copy(io.Discard, r.Body)
+				if up.Calls() < 3 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}
+			srv := httptest.NewServer(up)
+			defer srv.Close()
+
+			transport := httpclient.NewRetryTransport(nil, 3)
+			transport.BaseDelay = time.Millisecond
+
+			store := newMemStore()
+			store.Set(t.Context(), sessionKeyPrefix+testSessionToken, testHHToken, sessionTTL)
+			h := NewHandler(store, &http.Client{Transport: transport}, srv.URL, CacheConfig{})
+			server := middleware.MaxBodySizeMiddleware(1 << 10)(http.HandlerFunc(h.HandleRequest))
+
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			req := httptest.NewRequest(tt.method, tt.target, body)
+			req.Header.Set("X-Proxy-Session-Token", testSessionToken)
+
+			rec := httptest.NewRecorder()
+			server.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if up.Calls() != tt.wantCalls {
+				t.Fatalf("upstream calls = %d, want %d", up.Calls(), tt.wantCalls)
+			}
+		})
+	}
+}
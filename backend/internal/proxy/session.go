@@ -0,0 +1,88 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	sessionTTL       = time.Hour
+	sessionKeyPrefix = "proxy_session:"
+)
+
+type sessionResponse struct {
+	SessionToken string `json:"session_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// CreateSession обменивает access token HH.ru на токен сессии прокси.
+// Access token передается один раз, проверяется запросом GET /me к HH.ru
+// и хранится только в Redis, поэтому он не попадает в заголовки
+// последующих запросов и логи доступа прокси.
+func (h *Handler) CreateSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userToken := r.Header.Get("X-HH-Access-Token")
+	if userToken == "" {
+		http.Error(w, "Access token required", http.StatusBadRequest)
+		return
+	}
+
+	status, err := h.checkToken(r, userToken)
+	if err != nil {
+		http.Error(w, "Failed to reach HH.ru", http.StatusBadGateway)
+		return
+	}
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		http.Error(w, "Invalid HH.ru access token", http.StatusUnauthorized)
+		return
+	case status < 200 || status >= 300:
+		http.Error(w, "Failed to verify HH.ru access token", http.StatusBadGateway)
+		return
+	}
+
+	sessionToken := uuid.NewString()
+	if err := h.store.Set(r.Context(), sessionKeyPrefix+sessionToken, userToken, sessionTTL); err != nil {
+		http.Error(w, "Failed to create proxy session", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sessionResponse{
+		SessionToken: sessionToken,
+		ExpiresIn:    int(sessionTTL.Seconds()),
+	})
+}
+
+// checkToken запрашивает GET /me с токеном пользователя и возвращает
+// статус ответа HH.ru
+func (h *Handler) checkToken(r *http.Request, userToken string) (int, error) {
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, h.baseURL+"/me", nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Authorization", "Bearer "+userToken)
+	req.Header.Set("User-Agent", r.Header.Get("User-Agent"))
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode, nil
+}
+
+func (h *Handler) lookupSession(ctx context.Context, sessionToken string) (string, error) {
+	return h.store.Get(ctx, sessionKeyPrefix+sessionToken)
+}
@@ -0,0 +1,89 @@
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func createSession(h *Handler, method, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/api/proxy/session", nil)
+	if token != "" {
+		req.Header.Set("X-HH-Access-Token", token)
+	}
+
+	rec := httptest.NewRecorder()
+	h.CreateSession(rec, req)
+	return rec
+}
+
+// meUpstream отвечает на GET /me: 200 для testHHToken, иначе status
+func meUpstream(t *testing.T, status int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/me" {
+			t.Errorf("path = %q, want /me", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer "+testHHToken {
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(`{"id":"1"}`))
+	}
+}
+
+func TestCreateSession_ValidToken(t *testing.T) {
+	h, store, up := newTestHandler(t, meUpstream(t, http.StatusForbidden))
+
+	rec := createSession(h, http.MethodPost, testHHToken)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if up.Calls() != 1 {
+		t.Fatalf("upstream calls = %d, want 1", up.Calls())
+	}
+
+	var resp sessionResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.ExpiresIn != int(sessionTTL.Seconds()) {
+		t.Fatalf("expires_in = %d", resp.ExpiresIn)
+	}
+
+	stored, err := store.Get(t.Context(), sessionKeyPrefix+resp.SessionToken)
+	if err != nil || stored != testHHToken {
+		t.Fatalf("stored token = %q, %v", stored, err)
+	}
+}
+
+func TestCreateSession_Rejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		token    string
+		hhStatus int
+		want     int
+	}{
+		{"wrong method", http.MethodGet, testHHToken, http.StatusUnauthorized, http.StatusMethodNotAllowed},
+		{"missing token", http.MethodPost, "", http.StatusUnauthorized, http.StatusBadRequest},
+		{"token rejected by HH.ru", http.MethodPost, "forged", http.StatusUnauthorized, http.StatusUnauthorized},
+		{"token forbidden by HH.ru", http.MethodPost, "forged", http.StatusForbidden, http.StatusUnauthorized},
+		{"HH.ru error", http.MethodPost, "forged", http.StatusInternalServerError, http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, store, _ := newTestHandler(t, meUpstream(t, tt.hhStatus))
+			sessionsBefore := len(store.data)
+
+			rec := createSession(h, tt.method, tt.token)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if len(store.data) != sessionsBefore {
+				t.Fatal("no session must be stored")
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ErrNotFound возвращается, когда ключ отсутствует в Redis
+var ErrNotFound = errors.New("key not found")
+
+// RedisClient добавляет ко всем ключам префикс "{Namespace}:", чтобы
+// окружения, работающие с одним Redis, не пересекались по ключам
+type RedisClient struct {
+	client    *redis.Client
+	Namespace string
+}
+
+func NewRedisClient(redisURL, namespace string) (*RedisClient, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis url: %w", err)
+	}
+
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	return &RedisClient{client: client, Namespace: namespace}, nil
+}
+
+// NamespacedKey возвращает ключ в том виде, в каком он хранится в Redis
+func (r *RedisClient) NamespacedKey(key string) string {
+	if r.Namespace == "" {
+		return key
+	}
+	return r.Namespace + ":" + key
+}
+
+func (r *RedisClient) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	return r.client.Set(ctx, r.NamespacedKey(key), value, ttl).Err()
+}
+
+func (r *RedisClient) Get(ctx context.Context, key string) (string, error) {
+	value, err := r.client.Get(ctx, r.NamespacedKey(key)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrNotFound
+	}
+	return value, err
+}
+
+func (r *RedisClient) Delete(ctx context.Context, keys ...string) error {
+	namespaced := make([]string, len(keys))
+	for i, key := range keys {
+		namespaced[i] = r.NamespacedKey(key)
+	}
+	return r.client.Del(ctx, namespaced...).Err()
+}
+
+// AddToSet добавляет member в множество setKey и продлевает TTL множества
+func (r *RedisClient) AddToSet(ctx context.Context, setKey, member string, ttl time.Duration) error {
+	key := r.NamespacedKey(setKey)
+
+	pipe := r.client.TxPipeline()
+	pipe.SAdd(ctx, key, member)
+	pipe.Expire(ctx, key, ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// DeleteSetMembers удаляет ключи, перечисленные в множестве setKey, и само
+// множество
+func (r *RedisClient) DeleteSetMembers(ctx context.Context, setKey string) error {
+	key := r.NamespacedKey(setKey)
+
+	members, err := r.client.SMembers(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := []string{key}
+	for _, member := range members {
+		keys = append(keys, r.NamespacedKey(member))
+	}
+	return r.client.Del(ctx, keys...).Err()
+}
+
+func (r *RedisClient) Close() error {
+	return r.client.Close()
+}